@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+const (
+	replayWindow    = 5 * time.Minute
+	nonceCacheSize  = 10000
+	ipLimiterSize   = 10000
+	notifyRatePerS  = 5
+	notifyRateBurst = 10
+)
+
+// nonceCache は直近に見た X-Nonce を LRU で保持し、リプレイ攻撃を拒否する
+type nonceCache struct {
+	cache *lru.Cache[string, time.Time]
+}
+
+func newNonceCache(capacity int) *nonceCache {
+	cache, err := lru.New[string, time.Time](capacity)
+	if err != nil {
+		panic(err)
+	}
+	return &nonceCache{cache: cache}
+}
+
+// seen は nonce を replayWindow 以内に既に見ていれば true を返し、そうでなければ記録して false を返す
+func (c *nonceCache) seen(nonce string, now time.Time) bool {
+	if seenAt, ok := c.cache.Get(nonce); ok && now.Sub(seenAt) <= replayWindow {
+		return true
+	}
+	c.cache.Add(nonce, now)
+	return false
+}
+
+// ipLimiters はクライアント IP ごとのトークンバケットレートリミッタを LRU で保持する。
+// 容量を超えたら最も使われていない IP のリミッタを追い出し、メモリを無制限に増やさない
+type ipLimiters struct {
+	cache *lru.Cache[string, *rate.Limiter]
+	r     rate.Limit
+	burst int
+}
+
+func newIPLimiters(capacity int, r rate.Limit, burst int) *ipLimiters {
+	cache, err := lru.New[string, *rate.Limiter](capacity)
+	if err != nil {
+		panic(err)
+	}
+	return &ipLimiters{cache: cache, r: r, burst: burst}
+}
+
+func (l *ipLimiters) allow(key string) bool {
+	lim, ok := l.cache.Get(key)
+	if !ok {
+		lim = rate.NewLimiter(l.r, l.burst)
+		l.cache.Add(key, lim)
+	}
+	return lim.Allow()
+}
+
+// requireSignedRequest は /notify を HMAC 署名・タイムスタンプ・ナンス・送信元 IP ごとの
+// レートリミットで保護するミドルウェア。NOTIFY_SECRET が未設定の場合は検証をスキップする
+// （開発環境向け）。レートリミットは署名検証が通った後、検証済みの RemoteAddr をキーとして
+// 適用する — クライアントが自由に偽装できるヘッダーをキーにすると、ヘッダーを変えるだけで
+// レートリミットを回避されてしまうため
+func requireSignedRequest(next http.Handler) http.Handler {
+	secret := os.Getenv("NOTIFY_SECRET")
+	if secret == "" {
+		return next
+	}
+
+	nonces := newNonceCache(nonceCacheSize)
+	limiters := newIPLimiters(ipLimiterSize, notifyRatePerS, notifyRateBurst)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sigHeader := r.Header.Get("X-Signature")
+		tsHeader := r.Header.Get("X-Timestamp")
+		if sigHeader == "" || tsHeader == "" {
+			http.Error(w, "missing signature headers", http.StatusUnauthorized)
+			return
+		}
+
+		ts, err := strconv.ParseInt(tsHeader, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid X-Timestamp", http.StatusUnauthorized)
+			return
+		}
+
+		now := time.Now()
+		reqTime := time.Unix(ts, 0)
+		if now.Sub(reqTime) > replayWindow || reqTime.Sub(now) > replayWindow {
+			http.Error(w, "timestamp outside replay window", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !validSignature(secret, body, sigHeader) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		nonce := r.Header.Get("X-Nonce")
+		if nonce == "" {
+			nonce = sigHeader
+		}
+		if nonces.seen(nonce, now) {
+			http.Error(w, "replayed request", http.StatusUnauthorized)
+			return
+		}
+
+		// ここまで来て初めてリクエストは認証済み。レートリミットは偽装できない
+		// RemoteAddr をキーにして、認証後に適用する
+		if !limiters.allow(clientKey(r)) {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validSignature は X-Signature が生のリクエストボディに対する HMAC-SHA256 と一致するか検証する
+func validSignature(secret string, body []byte, header string) bool {
+	expected := strings.TrimPrefix(header, "sha256=")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	computed := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(computed), []byte(expected))
+}
+
+// clientKey はレートリミットのキーとして使う送信元アドレスを返す。プロキシ越しに公開する
+// 場合は ListenAndServe の手前に信頼できるリバースプロキシを置き、RemoteAddr を
+// 実クライアント IP に書き換えさせる運用を前提とする
+func clientKey(r *http.Request) string {
+	return r.RemoteAddr
+}
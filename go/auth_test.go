@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidSignature(t *testing.T) {
+	secret := "super-secret"
+	body := []byte(`{"triangle":"BTC/ETH/USDT"}`)
+
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		header string
+		want   bool
+	}{
+		{"matching signature", secret, body, sign(secret, body), true},
+		{"wrong secret", "other-secret", body, sign(secret, body), false},
+		{"tampered body", secret, []byte(`{"triangle":"ETH/BNB/USDT"}`), sign(secret, body), false},
+		{"missing sha256 prefix still compares hex", secret, body, sign(secret, body)[len("sha256="):], true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validSignature(tt.secret, tt.body, tt.header); got != tt.want {
+				t.Errorf("validSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonceCacheRejectsReplayWithinWindow(t *testing.T) {
+	nonces := newNonceCache(10)
+	now := time.Now()
+
+	if nonces.seen("abc", now) {
+		t.Fatal("first sighting of nonce should not be a replay")
+	}
+	if !nonces.seen("abc", now.Add(time.Minute)) {
+		t.Fatal("repeating the nonce within the replay window should be rejected")
+	}
+}
+
+func TestNonceCacheAllowsReuseAfterWindowExpires(t *testing.T) {
+	nonces := newNonceCache(10)
+	now := time.Now()
+
+	nonces.seen("abc", now)
+	if nonces.seen("abc", now.Add(replayWindow+time.Second)) {
+		t.Fatal("nonce outside the replay window should not be treated as a replay")
+	}
+}
+
+func TestIPLimitersAllowsUpToBurstThenBlocks(t *testing.T) {
+	limiters := newIPLimiters(10, 1, 2)
+
+	if !limiters.allow("1.2.3.4") {
+		t.Fatal("first request within burst should be allowed")
+	}
+	if !limiters.allow("1.2.3.4") {
+		t.Fatal("second request within burst should be allowed")
+	}
+	if limiters.allow("1.2.3.4") {
+		t.Fatal("request beyond burst should be rate limited")
+	}
+}
+
+func TestIPLimitersTracksKeysIndependently(t *testing.T) {
+	limiters := newIPLimiters(10, 1, 1)
+
+	if !limiters.allow("1.2.3.4") {
+		t.Fatal("first client's first request should be allowed")
+	}
+	if limiters.allow("1.2.3.4") {
+		t.Fatal("first client's second request should be rate limited")
+	}
+	if !limiters.allow("5.6.7.8") {
+		t.Fatal("a different client should have its own independent bucket")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func readConfigFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// unmarshalConfig は拡張子に応じて YAML か JSON として v にデコードする
+func unmarshalConfig(path string, data []byte, v any) error {
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		return yaml.Unmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
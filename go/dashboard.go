@@ -0,0 +1,18 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var embeddedStatic embed.FS
+
+// dashboardFS は static/ を埋め込み FS のルートとして公開する
+var dashboardFS = func() fs.FS {
+	sub, err := fs.Sub(embeddedStatic, "static")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}()
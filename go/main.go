@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 )
 
 // NotifyRequest は Python から送られてくるリクエストの構造体
@@ -19,31 +20,83 @@ type NotifyRequest struct {
 	Threshold  float64 `json:"threshold"`
 }
 
-func notifyHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+func notifyHandler(store *Store, router *Router, hub *Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-	var req NotifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
+		var req NotifyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// 受け取ったデータをログに出力
+		log.Printf("利益検出: %s %s 利益率 %.2f%% (%.2f USDT) (volatility=%.2f%%, slippage=%.4f, trend=%s, threshold=%.2f%%)",
+			req.Triangle, req.Direction, req.ProfitRate, req.ProfitUSDT, req.Volatility, req.Slippage, req.Trend, req.Threshold)
+
+		if _, err := store.Insert(req); err != nil {
+			log.Printf("保存エラー: %v", err)
+			http.Error(w, "Failed to persist opportunity", http.StatusInternalServerError)
+			return
+		}
+
+		if router != nil {
+			router.Dispatch(req)
+		}
 
-	// 受け取ったデータをログに出力
-	log.Printf("利益検出: %s %s 利益率 %.2f%% (%.2f USDT) (volatility=%.2f%%, slippage=%.4f, trend=%s, threshold=%.2f%%)",
-		req.Triangle, req.Direction, req.ProfitRate, req.ProfitUSDT, req.Volatility, req.Slippage, req.Trend, req.Threshold)
+		hub.Publish(req)
+		recordMetrics(req)
 
-	// 成功レスポンスを返す
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "通知を受け取りました")
+		// 成功レスポンスを返す
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "通知を受け取りました")
+	}
 }
 
 func main() {
-	http.HandleFunc("/notify", notifyHandler)
+	store, err := openStore()
+	if err != nil {
+		log.Fatalf("DB 初期化エラー: %v", err)
+	}
+
+	router, err := loadRouter()
+	if err != nil {
+		log.Fatalf("通知 sink の初期化エラー: %v", err)
+	}
+
+	hub := newHub()
+
+	http.Handle("/notify", requireSignedRequest(notifyHandler(store, router, hub)))
+	http.HandleFunc("/opportunities", opportunitiesHandler(store))
+	http.HandleFunc("/opportunities/stats", opportunitiesStatsHandler(store))
+	http.HandleFunc("/stream", streamHandler(hub))
+	http.HandleFunc("/events", eventsHandler(hub))
+	http.Handle("/rpc", newRPCHandler(store, router, hub))
+	http.Handle("/metrics", metricsHandler())
+	http.HandleFunc("/healthz", healthzHandler())
+	http.HandleFunc("/readyz", readyzHandler(store))
+	http.Handle("/", dashboardHandler())
+
 	log.Println("Go サーバーをポート 8080 で起動します...")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
 		log.Fatalf("サーバー起動エラー: %v", err)
 	}
 }
+
+// loadRouter は NOTIFY_SINKS_CONFIG 環境変数で指定された設定ファイルから Router を組み立てる。
+// 未設定の場合は sink なし（nil）で起動し、従来通りログ出力のみを行う
+func loadRouter() (*Router, error) {
+	path := os.Getenv("NOTIFY_SINKS_CONFIG")
+	if path == "" {
+		return nil, nil
+	}
+
+	cfg, err := loadNotifierConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return newRouter(cfg)
+}
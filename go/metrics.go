@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// profitRateBuckets は 0.05%〜2% の利益率帯をカバーするヒストグラムバケット
+var profitRateBuckets = []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1.0, 1.5, 2.0}
+
+var (
+	opportunitiesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_opportunities_total",
+		Help: "検出・受理された裁定機会の総数",
+	}, []string{"triangle", "direction", "trend"})
+
+	profitRateHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "arb_profit_rate",
+		Help:    "裁定機会の利益率 (%)",
+		Buckets: profitRateBuckets,
+	})
+
+	profitUSDTHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "arb_profit_usdt",
+		Help: "裁定機会の想定利益 (USDT)",
+	})
+
+	slippageHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "arb_slippage",
+		Help: "裁定機会検出時のスリッページ",
+	})
+
+	volatilityHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "arb_volatility",
+		Help: "裁定機会検出時のボラティリティ (%)",
+	})
+
+	thresholdGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_threshold",
+		Help: "三角ペアごとの現在の利益率閾値 (%)",
+	}, []string{"triangle"})
+)
+
+// recordMetrics は受理した NotifyRequest を Prometheus の各メトリクスに反映する。
+// arb_threshold は Config.SetThresholds (rpc.go) が設定する構成値が正であり、
+// ここでは未設定の三角ペアに対する初期値の補完のみ行う（二重の書き手による値のフラップを防ぐため）
+func recordMetrics(req NotifyRequest) {
+	opportunitiesTotal.WithLabelValues(req.Triangle, req.Direction, req.Trend).Inc()
+	profitRateHistogram.Observe(req.ProfitRate)
+	profitUSDTHistogram.Observe(req.ProfitUSDT)
+	slippageHistogram.Observe(req.Slippage)
+	volatilityHistogram.Observe(req.Volatility)
+	bootstrapThreshold(req.Triangle, req.Threshold)
+}
+
+// metricsHandler は /metrics で Prometheus 形式のメトリクスを公開する
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// healthzHandler は /healthz でプロセスの生存を返す（常に 200）
+func healthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}
+}
+
+// readyzHandler は /readyz で DB 接続が確立しているかを確認して返す
+func readyzHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := store.db.PingContext(r.Context()); err != nil {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// opportunitiesHandler は GET /opportunities で保存済みの裁定機会を
+// triangle / direction / min_profit_rate / since / until / limit で絞り込んで返す
+func opportunitiesHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		f, err := parseOpportunityFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		opps, err := store.Query(f)
+		if err != nil {
+			http.Error(w, "検索に失敗しました", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(opps)
+	}
+}
+
+func parseOpportunityFilter(r *http.Request) (OpportunityFilter, error) {
+	q := r.URL.Query()
+	f := OpportunityFilter{
+		Triangle:  q.Get("triangle"),
+		Direction: q.Get("direction"),
+	}
+
+	if v := q.Get("min_profit_rate"); v != "" {
+		min, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return f, errors.New("min_profit_rate が不正です")
+		}
+		f.MinProfit, f.HasMin = min, true
+	}
+
+	if v := q.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.New("since は RFC3339 形式で指定してください")
+		}
+		f.Since, f.HasSince = t, true
+	}
+
+	if v := q.Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.New("until は RFC3339 形式で指定してください")
+		}
+		f.Until, f.HasUntil = t, true
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return f, errors.New("limit が不正です")
+		}
+		f.Limit = limit
+	}
+
+	return f, nil
+}
+
+// opportunitiesStatsHandler は GET /opportunities/stats で三角ペアごとの
+// 件数・平均利益率・閾値超え率を集計して返す。window=1h/24h でローリング期間を指定できる（既定 24h）
+func opportunitiesStatsHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		window := 24 * time.Hour
+		if v := r.URL.Query().Get("window"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(w, "window が不正です", http.StatusBadRequest)
+				return
+			}
+			window = d
+		}
+
+		minProfit := 0.0
+		if v := r.URL.Query().Get("min_profit_rate"); v != "" {
+			m, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				http.Error(w, "min_profit_rate が不正です", http.StatusBadRequest)
+				return
+			}
+			minProfit = m
+		}
+
+		stats, err := store.Aggregate(time.Now().UTC().Add(-window), minProfit)
+		if err != nil {
+			http.Error(w, "集計に失敗しました", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// rpcServices は /rpc に登録される JSON-RPC 2.0 サービス群をまとめて保持する
+type rpcServices struct {
+	store   *Store
+	router  *Router
+	hub     *Hub
+	control *controlState
+}
+
+// controlState は Control.Pause/Resume で切り替わる稼働状態
+type controlState struct {
+	mu     sync.RWMutex
+	paused bool
+}
+
+func (c *controlState) isPaused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused
+}
+
+func (c *controlState) setPaused(paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = paused
+}
+
+// newRPCHandler は Notify/Config/Control サービスを登録した JSON-RPC 2.0 ハンドラを返す
+func newRPCHandler(store *Store, router *Router, hub *Hub) http.Handler {
+	services := &rpcServices{store: store, router: router, hub: hub, control: &controlState{}}
+
+	s := rpc.NewServer()
+	s.RegisterCodec(json2.NewCodec(), "application/json")
+	s.RegisterService(&NotifyService{services}, "Notify")
+	s.RegisterService(&ConfigService{services}, "Config")
+	s.RegisterService(&ControlService{services}, "Control")
+	return s
+}
+
+// --- Notify service ---
+
+// NotifyService は裁定機会の送信・参照を JSON-RPC 経由で提供する
+type NotifyService struct{ svc *rpcServices }
+
+// NotifySubmitArgs は Notify.Submit の引数
+type NotifySubmitArgs struct {
+	NotifyRequest
+}
+
+// NotifySubmitReply は Notify.Submit の戻り値
+type NotifySubmitReply struct {
+	ID int64 `json:"id"`
+}
+
+// Submit は NotifyRequest を保存し、sink / stream 購読者へ配送する
+func (s *NotifyService) Submit(r *http.Request, args *NotifySubmitArgs, reply *NotifySubmitReply) error {
+	if s.svc.control.isPaused() {
+		return &json2.Error{Code: json2.E_SERVER, Message: "notifier is paused"}
+	}
+
+	opp, err := s.svc.store.Insert(args.NotifyRequest)
+	if err != nil {
+		return err
+	}
+
+	if s.svc.router != nil {
+		s.svc.router.Dispatch(args.NotifyRequest)
+	}
+	s.svc.hub.Publish(args.NotifyRequest)
+	recordMetrics(args.NotifyRequest)
+
+	reply.ID = opp.ID
+	return nil
+}
+
+// NotifyRecentArgs は Notify.Recent の引数
+type NotifyRecentArgs struct {
+	Limit    int    `json:"limit"`
+	Triangle string `json:"triangle"`
+}
+
+// NotifyRecentReply は Notify.Recent の戻り値
+type NotifyRecentReply struct {
+	Opportunities []Opportunity `json:"opportunities"`
+}
+
+// Recent は直近の裁定機会を limit 件、任意で triangle で絞って返す
+func (s *NotifyService) Recent(r *http.Request, args *NotifyRecentArgs, reply *NotifyRecentReply) error {
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	opps, err := s.svc.store.Query(OpportunityFilter{Triangle: args.Triangle, Limit: limit})
+	if err != nil {
+		return err
+	}
+
+	reply.Opportunities = opps
+	return nil
+}
+
+// --- Config service ---
+
+// thresholds は三角ペアごとの閾値を保持する（プロセス内メモリ、起動毎にリセットされる）。
+// arb_threshold ゲージの唯一の書き手であり、Config.SetThresholds による明示的な設定が
+// 常に優先される。bootstrapThreshold はまだ設定のない三角ペアの初期値補完にのみ使う
+var thresholds = struct {
+	mu sync.RWMutex
+	m  map[string]float64
+}{m: make(map[string]float64)}
+
+// bootstrapThreshold は triangle にまだ閾値が設定されていない場合に限り、観測された
+// NotifyRequest.Threshold で初期値を補完する。Config.SetThresholds で明示的に設定済みの
+// 値を上書きすることはない
+func bootstrapThreshold(triangle string, value float64) {
+	thresholds.mu.Lock()
+	defer thresholds.mu.Unlock()
+
+	if _, ok := thresholds.m[triangle]; ok {
+		return
+	}
+	thresholds.m[triangle] = value
+	thresholdGauge.WithLabelValues(triangle).Set(value)
+}
+
+// ConfigService は裁定検出の閾値設定を JSON-RPC 経由で公開・更新する
+type ConfigService struct{ svc *rpcServices }
+
+// ConfigGetThresholdsArgs は Config.GetThresholds の引数（なし）
+type ConfigGetThresholdsArgs struct{}
+
+// ConfigGetThresholdsReply は Config.GetThresholds の戻り値
+type ConfigGetThresholdsReply struct {
+	Thresholds map[string]float64 `json:"thresholds"`
+}
+
+// GetThresholds は現在設定されている三角ペアごとの閾値を返す
+func (s *ConfigService) GetThresholds(r *http.Request, args *ConfigGetThresholdsArgs, reply *ConfigGetThresholdsReply) error {
+	thresholds.mu.RLock()
+	defer thresholds.mu.RUnlock()
+
+	out := make(map[string]float64, len(thresholds.m))
+	for k, v := range thresholds.m {
+		out[k] = v
+	}
+	reply.Thresholds = out
+	return nil
+}
+
+// ConfigSetThresholdsArgs は Config.SetThresholds の引数
+type ConfigSetThresholdsArgs struct {
+	Triangle string  `json:"triangle"`
+	Value    float64 `json:"value"`
+}
+
+// ConfigSetThresholdsReply は Config.SetThresholds の戻り値
+type ConfigSetThresholdsReply struct {
+	OK bool `json:"ok"`
+}
+
+// SetThresholds は三角ペアの利益率閾値を更新する。Python 側の arb エンジンと
+// Go 側のダッシュボードが互いにこの値を読み書きすることを想定している
+func (s *ConfigService) SetThresholds(r *http.Request, args *ConfigSetThresholdsArgs, reply *ConfigSetThresholdsReply) error {
+	if args.Triangle == "" {
+		return &json2.Error{Code: json2.E_BAD_PARAMS, Message: "triangle is required"}
+	}
+
+	thresholds.mu.Lock()
+	thresholds.m[args.Triangle] = args.Value
+	thresholds.mu.Unlock()
+	thresholdGauge.WithLabelValues(args.Triangle).Set(args.Value)
+
+	reply.OK = true
+	return nil
+}
+
+// --- Control service ---
+
+// ControlService は通知処理全体の一時停止・再開を JSON-RPC 経由で制御する
+type ControlService struct{ svc *rpcServices }
+
+// ControlPauseArgs は Control.Pause / Control.Resume の引数（なし）
+type ControlPauseArgs struct{}
+
+// ControlPauseReply は Control.Pause / Control.Resume の戻り値
+type ControlPauseReply struct {
+	Paused bool      `json:"paused"`
+	At     time.Time `json:"at"`
+}
+
+// Pause は Notify.Submit の処理を一時停止する
+func (s *ControlService) Pause(r *http.Request, args *ControlPauseArgs, reply *ControlPauseReply) error {
+	s.svc.control.setPaused(true)
+	reply.Paused = true
+	reply.At = time.Now().UTC()
+	return nil
+}
+
+// Resume は Notify.Submit の処理を再開する
+func (s *ControlService) Resume(r *http.Request, args *ControlPauseArgs, reply *ControlPauseReply) error {
+	s.svc.control.setPaused(false)
+	reply.Paused = false
+	reply.At = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Sink は裁定機会の通知を外部へ転送する送信先
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, req NotifyRequest) error
+}
+
+// SinkFilter は Sink ごとに通知を絞り込む条件。ゼロ値のフィールドは「制限なし」を意味する
+type SinkFilter struct {
+	MinProfitRate float64  `json:"min_profit_rate" yaml:"min_profit_rate"`
+	MinProfitUSDT float64  `json:"min_profit_usdt" yaml:"min_profit_usdt"`
+	Triangles     []string `json:"triangles" yaml:"triangles"`
+	Trend         string   `json:"trend" yaml:"trend"`
+}
+
+// Matches は req がこのフィルタを満たすかどうかを返す
+func (f SinkFilter) Matches(req NotifyRequest) bool {
+	if req.ProfitRate < f.MinProfitRate {
+		return false
+	}
+	if req.ProfitUSDT < f.MinProfitUSDT {
+		return false
+	}
+	if f.Trend != "" && req.Trend != f.Trend {
+		return false
+	}
+	if len(f.Triangles) > 0 {
+		found := false
+		for _, t := range f.Triangles {
+			if t == req.Triangle {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// SinkConfig は設定ファイル中の 1 つの sink エントリ
+type SinkConfig struct {
+	Type       string     `json:"type" yaml:"type"` // slack, discord, telegram, webhook, email
+	Filter     SinkFilter `json:"filter" yaml:"filter"`
+	RatePerSec float64    `json:"rate_per_sec" yaml:"rate_per_sec"`
+	Burst      int        `json:"burst" yaml:"burst"`
+
+	// webhook/slack/discord
+	URL string `json:"url" yaml:"url"`
+
+	// telegram
+	BotToken string `json:"bot_token" yaml:"bot_token"`
+	ChatID   string `json:"chat_id" yaml:"chat_id"`
+
+	// email
+	SMTPAddr string   `json:"smtp_addr" yaml:"smtp_addr"`
+	From     string   `json:"from" yaml:"from"`
+	To       []string `json:"to" yaml:"to"`
+}
+
+// NotifierConfig はルート設定ファイル全体（sinks のリスト）
+type NotifierConfig struct {
+	Sinks []SinkConfig `json:"sinks" yaml:"sinks"`
+}
+
+// loadNotifierConfig は YAML/JSON 設定ファイルを読み込む。拡張子が .yaml/.yml なら YAML として、
+// それ以外は JSON としてパースする
+func loadNotifierConfig(path string) (NotifierConfig, error) {
+	var cfg NotifierConfig
+	data, err := readConfigFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	if err := unmarshalConfig(path, data, &cfg); err != nil {
+		return cfg, fmt.Errorf("設定ファイルの解析に失敗しました: %w", err)
+	}
+	return cfg, nil
+}
+
+// routedSink は Sink を SinkFilter とレートリミッタで包んだもの
+type routedSink struct {
+	sink    Sink
+	filter  SinkFilter
+	limiter *rate.Limiter
+}
+
+// Router は複数の Sink へフィルタ・レート制御・リトライ付きで配送する
+type Router struct {
+	sinks []routedSink
+}
+
+// newRouter は設定から Router を組み立てる
+func newRouter(cfg NotifierConfig) (*Router, error) {
+	r := &Router{}
+	for _, sc := range cfg.Sinks {
+		sink, err := newSink(sc)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q の初期化に失敗しました: %w", sc.Type, err)
+		}
+
+		rps := sc.RatePerSec
+		if rps <= 0 {
+			rps = 1
+		}
+		burst := sc.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+
+		r.sinks = append(r.sinks, routedSink{
+			sink:    sink,
+			filter:  sc.Filter,
+			limiter: rate.NewLimiter(rate.Limit(rps), burst),
+		})
+	}
+	return r, nil
+}
+
+// dispatchTimeout は各 Sink への配送（レート待ち + リトライ）に許す最大時間
+const dispatchTimeout = 30 * time.Second
+
+// Dispatch は req を条件に合う全ての Sink へ非同期に送信する。個々の Sink の失敗は
+// ログに残すのみで、他の Sink の配送をブロックしない。配送は呼び出し元のリクエストが
+// 完了した後も続く可能性があるため、リクエストコンテキストではなく独立したコンテキストで行う
+func (r *Router) Dispatch(req NotifyRequest) {
+	for _, rs := range r.sinks {
+		if !rs.filter.Matches(req) {
+			continue
+		}
+		go func(rs routedSink) {
+			ctx, cancel := context.WithTimeout(context.Background(), dispatchTimeout)
+			defer cancel()
+
+			if err := rs.limiter.Wait(ctx); err != nil {
+				logSinkError(rs.sink, err)
+				return
+			}
+			if err := sendWithRetry(ctx, rs.sink, req); err != nil {
+				logSinkError(rs.sink, err)
+			}
+		}(rs)
+	}
+}
+
+// sendWithRetry は Send を指数バックオフで最大 3 回まで再試行する
+func sendWithRetry(ctx context.Context, sink Sink, req NotifyRequest) error {
+	const maxAttempts = 3
+	backoff := 500 * time.Millisecond
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err := sink.Send(ctx, req)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+	return lastErr
+}
+
+func newSink(sc SinkConfig) (Sink, error) {
+	switch sc.Type {
+	case "slack":
+		return &webhookSink{name: "slack", url: sc.URL, payload: slackPayload}, nil
+	case "discord":
+		return &webhookSink{name: "discord", url: sc.URL, payload: discordPayload}, nil
+	case "webhook":
+		return &webhookSink{name: "webhook", url: sc.URL, payload: rawPayload}, nil
+	case "telegram":
+		return &telegramSink{botToken: sc.BotToken, chatID: sc.ChatID}, nil
+	case "email":
+		return &emailSink{smtpAddr: sc.SMTPAddr, from: sc.From, to: sc.To}, nil
+	default:
+		return nil, fmt.Errorf("未知の sink タイプです: %s", sc.Type)
+	}
+}
+
+func formatMessage(req NotifyRequest) string {
+	return fmt.Sprintf("裁定機会検出: %s %s 利益率 %.2f%% (%.2f USDT) trend=%s",
+		req.Triangle, req.Direction, req.ProfitRate, req.ProfitUSDT, req.Trend)
+}
+
+// webhookSink は Slack/Discord 互換の incoming webhook、もしくは素の HTTP POST
+type webhookSink struct {
+	name    string
+	url     string
+	payload func(NotifyRequest) any
+}
+
+func (s *webhookSink) Name() string { return s.name }
+
+func (s *webhookSink) Send(ctx context.Context, req NotifyRequest) error {
+	body, err := json.Marshal(s.payload(req))
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s が %d を返しました", s.name, resp.StatusCode)
+	}
+	return nil
+}
+
+func slackPayload(req NotifyRequest) any {
+	return map[string]string{"text": formatMessage(req)}
+}
+
+func discordPayload(req NotifyRequest) any {
+	return map[string]string{"content": formatMessage(req)}
+}
+
+func rawPayload(req NotifyRequest) any {
+	return req
+}
+
+// telegramSink は Telegram Bot API 経由でメッセージを送る
+type telegramSink struct {
+	botToken string
+	chatID   string
+}
+
+func (s *telegramSink) Name() string { return "telegram" }
+
+func (s *telegramSink) Send(ctx context.Context, req NotifyRequest) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.botToken)
+	body, err := json.Marshal(map[string]string{
+		"chat_id": s.chatID,
+		"text":    formatMessage(req),
+	})
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram が %d を返しました", resp.StatusCode)
+	}
+	return nil
+}
+
+// emailSink は SMTP 経由でメール通知を送る
+type emailSink struct {
+	smtpAddr string
+	from     string
+	to       []string
+}
+
+func (s *emailSink) Name() string { return "email" }
+
+func (s *emailSink) Send(ctx context.Context, req NotifyRequest) error {
+	msg := fmt.Sprintf("Subject: 裁定機会通知\r\n\r\n%s\r\n", formatMessage(req))
+	return smtp.SendMail(s.smtpAddr, nil, s.from, s.to, []byte(msg))
+}
+
+func logSinkError(sink Sink, err error) {
+	log.Printf("sink %s への配送に失敗しました: %v", sink.Name(), err)
+}
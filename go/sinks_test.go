@@ -0,0 +1,105 @@
+package main
+
+import "testing"
+
+func TestSinkFilterMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter SinkFilter
+		req    NotifyRequest
+		want   bool
+	}{
+		{
+			name:   "no restrictions matches anything",
+			filter: SinkFilter{},
+			req:    NotifyRequest{Triangle: "BTC/ETH/USDT", ProfitRate: 0.01},
+			want:   true,
+		},
+		{
+			name:   "below min profit rate",
+			filter: SinkFilter{MinProfitRate: 0.5},
+			req:    NotifyRequest{ProfitRate: 0.4},
+			want:   false,
+		},
+		{
+			name:   "at min profit rate",
+			filter: SinkFilter{MinProfitRate: 0.5},
+			req:    NotifyRequest{ProfitRate: 0.5},
+			want:   true,
+		},
+		{
+			name:   "below min profit usdt",
+			filter: SinkFilter{MinProfitUSDT: 5},
+			req:    NotifyRequest{ProfitUSDT: 4.99},
+			want:   false,
+		},
+		{
+			name:   "trend mismatch",
+			filter: SinkFilter{Trend: "up"},
+			req:    NotifyRequest{Trend: "down"},
+			want:   false,
+		},
+		{
+			name:   "trend match",
+			filter: SinkFilter{Trend: "up"},
+			req:    NotifyRequest{Trend: "up"},
+			want:   true,
+		},
+		{
+			name:   "triangle not in allowlist",
+			filter: SinkFilter{Triangles: []string{"BTC/ETH/USDT"}},
+			req:    NotifyRequest{Triangle: "ETH/BNB/USDT"},
+			want:   false,
+		},
+		{
+			name:   "triangle in allowlist",
+			filter: SinkFilter{Triangles: []string{"BTC/ETH/USDT"}},
+			req:    NotifyRequest{Triangle: "BTC/ETH/USDT"},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.req); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadNotifierConfigYAML はリポジトリ同梱の sinks.example.yaml を実際にパースし、
+// アンダースコア区切りのキーが yaml タグ経由で正しく読み込まれることを確認する
+func TestLoadNotifierConfigYAML(t *testing.T) {
+	cfg, err := loadNotifierConfig("sinks.example.yaml")
+	if err != nil {
+		t.Fatalf("loadNotifierConfig() error = %v", err)
+	}
+	if len(cfg.Sinks) != 3 {
+		t.Fatalf("got %d sinks, want 3", len(cfg.Sinks))
+	}
+
+	slack := cfg.Sinks[0]
+	if slack.Type != "slack" || slack.RatePerSec != 1 || slack.Burst != 2 {
+		t.Errorf("slack sink = %+v, want type=slack rate_per_sec=1 burst=2", slack)
+	}
+	if slack.Filter.MinProfitRate != 0.5 || slack.Filter.Trend != "up" {
+		t.Errorf("slack filter = %+v, want min_profit_rate=0.5 trend=up", slack.Filter)
+	}
+
+	discord := cfg.Sinks[1]
+	if discord.Filter.MinProfitUSDT != 5 {
+		t.Errorf("discord filter.MinProfitUSDT = %v, want 5", discord.Filter.MinProfitUSDT)
+	}
+	if len(discord.Filter.Triangles) != 1 || discord.Filter.Triangles[0] != "BTC/ETH/USDT" {
+		t.Errorf("discord filter.Triangles = %v, want [BTC/ETH/USDT]", discord.Filter.Triangles)
+	}
+
+	telegram := cfg.Sinks[2]
+	if telegram.BotToken != "123456:ABC-DEF" || telegram.ChatID != "-100123456" {
+		t.Errorf("telegram sink = %+v, want bot_token=123456:ABC-DEF chat_id=-100123456", telegram)
+	}
+	if telegram.RatePerSec != 0.5 {
+		t.Errorf("telegram.RatePerSec = %v, want 0.5", telegram.RatePerSec)
+	}
+}
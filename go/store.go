@@ -0,0 +1,180 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Opportunity は DB に永続化された NotifyRequest 1 件分
+type Opportunity struct {
+	ID         int64     `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+	NotifyRequest
+}
+
+// Store は裁定機会の永続化と検索を担う
+type Store struct {
+	db *sql.DB
+}
+
+// openStore は DB_DSN 環境変数（未設定なら ./arb.db）を使って Store を開く
+func openStore() (*Store, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "arb.db"
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("DB を開けませんでした: %w", err)
+	}
+
+	if _, err := db.Exec(schemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("スキーマの作成に失敗しました: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS opportunities (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	received_at DATETIME NOT NULL,
+	triangle TEXT NOT NULL,
+	direction TEXT NOT NULL,
+	profit_rate REAL NOT NULL,
+	profit_usdt REAL NOT NULL,
+	volatility REAL NOT NULL,
+	slippage REAL NOT NULL,
+	trend TEXT NOT NULL,
+	threshold REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_opportunities_triangle ON opportunities(triangle);
+CREATE INDEX IF NOT EXISTS idx_opportunities_received_at ON opportunities(received_at);
+`
+
+// Insert は受信した NotifyRequest にサーバー側タイムスタンプを付けて保存する
+func (s *Store) Insert(req NotifyRequest) (Opportunity, error) {
+	now := time.Now().UTC()
+	res, err := s.db.Exec(
+		`INSERT INTO opportunities (received_at, triangle, direction, profit_rate, profit_usdt, volatility, slippage, trend, threshold)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		now, req.Triangle, req.Direction, req.ProfitRate, req.ProfitUSDT, req.Volatility, req.Slippage, req.Trend, req.Threshold,
+	)
+	if err != nil {
+		return Opportunity{}, fmt.Errorf("保存に失敗しました: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Opportunity{}, fmt.Errorf("挿入 ID の取得に失敗しました: %w", err)
+	}
+
+	return Opportunity{ID: id, ReceivedAt: now, NotifyRequest: req}, nil
+}
+
+// OpportunityFilter は /opportunities のクエリ条件
+type OpportunityFilter struct {
+	Triangle  string
+	Direction string
+	MinProfit float64
+	HasMin    bool
+	Since     time.Time
+	HasSince  bool
+	Until     time.Time
+	HasUntil  bool
+	Limit     int
+}
+
+// Query はフィルタに合致する裁定機会を新しい順に返す
+func (s *Store) Query(f OpportunityFilter) ([]Opportunity, error) {
+	query := `SELECT id, received_at, triangle, direction, profit_rate, profit_usdt, volatility, slippage, trend, threshold
+		FROM opportunities WHERE 1=1`
+	var args []any
+
+	if f.Triangle != "" {
+		query += " AND triangle = ?"
+		args = append(args, f.Triangle)
+	}
+	if f.Direction != "" {
+		query += " AND direction = ?"
+		args = append(args, f.Direction)
+	}
+	if f.HasMin {
+		query += " AND profit_rate >= ?"
+		args = append(args, f.MinProfit)
+	}
+	if f.HasSince {
+		query += " AND received_at >= ?"
+		args = append(args, f.Since)
+	}
+	if f.HasUntil {
+		query += " AND received_at <= ?"
+		args = append(args, f.Until)
+	}
+
+	query += " ORDER BY received_at DESC"
+	if f.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("検索に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Opportunity
+	for rows.Next() {
+		var o Opportunity
+		if err := rows.Scan(&o.ID, &o.ReceivedAt, &o.Triangle, &o.Direction, &o.ProfitRate, &o.ProfitUSDT, &o.Volatility, &o.Slippage, &o.Trend, &o.Threshold); err != nil {
+			return nil, fmt.Errorf("行の読み取りに失敗しました: %w", err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// TriangleStats は三角通貨ペアごとの集計結果
+type TriangleStats struct {
+	Triangle      string  `json:"triangle"`
+	Count         int     `json:"count"`
+	AvgProfitRate float64 `json:"avg_profit_rate"`
+	HitRate       float64 `json:"hit_rate_above_threshold"`
+}
+
+// Aggregate は三角ペアごとの件数・平均利益率・閾値超え率を returns
+// since がゼロ値でない場合はそれ以降のレコードに限定する（1h/24h のローリング集計に利用）
+func (s *Store) Aggregate(since time.Time, minProfit float64) ([]TriangleStats, error) {
+	rows, err := s.db.Query(
+		`SELECT triangle,
+			COUNT(*) AS cnt,
+			AVG(profit_rate) AS avg_rate,
+			AVG(CASE WHEN profit_rate >= ? THEN 1.0 ELSE 0.0 END) AS hit_rate
+		 FROM opportunities
+		 WHERE received_at >= ?
+		 GROUP BY triangle
+		 ORDER BY cnt DESC`,
+		minProfit, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("集計に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TriangleStats
+	for rows.Next() {
+		var t TriangleStats
+		if err := rows.Scan(&t.Triangle, &t.Count, &t.AvgProfitRate, &t.HitRate); err != nil {
+			return nil, fmt.Errorf("集計行の読み取りに失敗しました: %w", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
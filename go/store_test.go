@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	t.Setenv("DB_DSN", ":memory:")
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.db.Close() })
+	return store
+}
+
+func TestStoreQueryFilters(t *testing.T) {
+	store := newTestStore(t)
+
+	seed := []NotifyRequest{
+		{Triangle: "BTC/ETH/USDT", Direction: "forward", ProfitRate: 0.3, ProfitUSDT: 1},
+		{Triangle: "BTC/ETH/USDT", Direction: "reverse", ProfitRate: 0.8, ProfitUSDT: 5},
+		{Triangle: "ETH/BNB/USDT", Direction: "forward", ProfitRate: 1.5, ProfitUSDT: 10},
+	}
+	for _, req := range seed {
+		if _, err := store.Insert(req); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter OpportunityFilter
+		want   int
+	}{
+		{"no filter", OpportunityFilter{}, 3},
+		{"by triangle", OpportunityFilter{Triangle: "BTC/ETH/USDT"}, 2},
+		{"by direction", OpportunityFilter{Direction: "forward"}, 2},
+		{"by min profit rate", OpportunityFilter{MinProfit: 0.8, HasMin: true}, 2},
+		{"by limit", OpportunityFilter{Limit: 1}, 1},
+		{"triangle and min profit combined", OpportunityFilter{Triangle: "BTC/ETH/USDT", MinProfit: 0.5, HasMin: true}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Query(%+v) returned %d rows, want %d", tt.filter, len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreQueryOrdersNewestFirst(t *testing.T) {
+	store := newTestStore(t)
+
+	first, err := store.Insert(NotifyRequest{Triangle: "BTC/ETH/USDT"})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	second, err := store.Insert(NotifyRequest{Triangle: "BTC/ETH/USDT"})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	got, err := store.Query(OpportunityFilter{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != second.ID || got[1].ID != first.ID {
+		t.Fatalf("Query() = %+v, want newest (%d) before oldest (%d)", got, second.ID, first.ID)
+	}
+}
+
+func TestStoreAggregate(t *testing.T) {
+	store := newTestStore(t)
+
+	for _, rate := range []float64{0.2, 0.6, 1.0} {
+		if _, err := store.Insert(NotifyRequest{Triangle: "BTC/ETH/USDT", ProfitRate: rate}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	stats, err := store.Aggregate(time.Now().Add(-time.Hour), 0.5)
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("Aggregate() returned %d triangles, want 1", len(stats))
+	}
+
+	got := stats[0]
+	if got.Triangle != "BTC/ETH/USDT" || got.Count != 3 {
+		t.Fatalf("Aggregate() = %+v, want triangle=BTC/ETH/USDT count=3", got)
+	}
+	if want := (0.2 + 0.6 + 1.0) / 3; got.AvgProfitRate < want-1e-9 || got.AvgProfitRate > want+1e-9 {
+		t.Errorf("AvgProfitRate = %v, want %v", got.AvgProfitRate, want)
+	}
+	if want := 2.0 / 3; got.HitRate < want-1e-9 || got.HitRate > want+1e-9 {
+		t.Errorf("HitRate = %v, want %v (two of three opportunities are >=0.5)", got.HitRate, want)
+	}
+}
+
+func TestStoreAggregateExcludesOlderThanSince(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.Insert(NotifyRequest{Triangle: "BTC/ETH/USDT", ProfitRate: 1.0}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	stats, err := store.Aggregate(time.Now().Add(time.Hour), 0)
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(stats) != 0 {
+		t.Fatalf("Aggregate(since=future) = %+v, want no rows", stats)
+	}
+}
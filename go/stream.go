@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamFilter は /stream, /events クライアントごとのサーバーサイド絞り込み条件
+type streamFilter struct {
+	triangle  string
+	minProfit float64
+}
+
+func (f streamFilter) matches(req NotifyRequest) bool {
+	if f.triangle != "" && req.Triangle != f.triangle {
+		return false
+	}
+	return req.ProfitRate >= f.minProfit
+}
+
+func parseStreamFilter(q url.Values) streamFilter {
+	f := streamFilter{triangle: q.Get("triangle")}
+	if v := q.Get("min_profit"); v != "" {
+		if m, err := strconv.ParseFloat(v, 64); err == nil {
+			f.minProfit = m
+		}
+	}
+	return f
+}
+
+// client は Hub に登録された 1 つの購読者
+type client struct {
+	filter streamFilter
+	ch     chan NotifyRequest
+}
+
+// Hub は受信した NotifyRequest を全購読クライアントへファンアウトする pub/sub ハブ
+type Hub struct {
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+// newHub は空の Hub を作る
+func newHub() *Hub {
+	return &Hub{clients: make(map[*client]struct{})}
+}
+
+// subscribe はフィルタ付きの購読クライアントを登録し、解除用の関数を返す
+func (h *Hub) subscribe(f streamFilter) *client {
+	c := &client{filter: f, ch: make(chan NotifyRequest, 32)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+func (h *Hub) unsubscribe(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	close(c.ch)
+}
+
+// Publish は req をフィルタに合致する全クライアントへ配送する。クライアントのバッファが
+// 詰まっている場合は Python 側の producer をブロックしないよう、そのメッセージを drop する
+func (h *Hub) Publish(req NotifyRequest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if !c.filter.matches(req) {
+			continue
+		}
+		select {
+		case c.ch <- req:
+		default:
+			log.Printf("stream クライアントへの配送をスキップしました（バッファ満杯）")
+		}
+	}
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// streamHandler は GET /stream で WebSocket 経由のリアルタイム配信を行う
+func streamHandler(hub *Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("WebSocket アップグレードに失敗しました: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		c := hub.subscribe(parseStreamFilter(r.URL.Query()))
+		defer hub.unsubscribe(c)
+
+		for req := range c.ch {
+			if err := conn.WriteJSON(req); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventsHandler は GET /events で Server-Sent Events によるフォールバック配信を行う
+func eventsHandler(hub *Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		c := hub.subscribe(parseStreamFilter(r.URL.Query()))
+		defer hub.unsubscribe(c)
+
+		ctx := r.Context()
+		for {
+			select {
+			case req, ok := <-c.ch:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(req)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", data)
+				flusher.Flush()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// dashboardHandler は embed.FS に埋め込まれた静的ダッシュボード（HTML/JS）を配信する
+func dashboardHandler() http.Handler {
+	return http.FileServer(http.FS(dashboardFS))
+}